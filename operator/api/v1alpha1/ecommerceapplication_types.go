@@ -0,0 +1,131 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConfigMapKeyRef points at a key inside a ConfigMap in the same namespace
+// as the ECommerceApplication.
+type ConfigMapKeyRef struct {
+	// Name of the ConfigMap holding the SQL to apply.
+	Name string `json:"name"`
+	// Key inside the ConfigMap whose value is the SQL to apply. Defaults to "schema.sql".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// CredentialSourceSpec selects which backend the Postgres connection
+// details are read from and where from. See pkg/credsource for the
+// supported Type values.
+type CredentialSourceSpec struct {
+	// Type selects the CredentialSource implementation to use, e.g.
+	// "IBMCloudBinding", "ZalandoPostgresql" or "RawSecret". Defaults to
+	// "IBMCloudBinding".
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Ref is the name of the Secret the chosen CredentialSource reads from.
+	// Defaults to PostgresSecretName.
+	// +optional
+	Ref string `json:"ref,omitempty"`
+}
+
+// ECommerceApplicationSpec defines the desired state of ECommerceApplication
+type ECommerceApplicationSpec struct {
+	// Size is the number of service-catalog replicas to run.
+	Size int32 `json:"size"`
+
+	// PostgresSecretName is the name of the secret carrying the Postgres
+	// connection details, read by the CredentialSource selected in
+	// CredentialSource (or by the IBM Cloud Operator binding convention if
+	// CredentialSource is unset).
+	PostgresSecretName string `json:"postgresSecretName"`
+
+	// TenantName identifies this application's tenant. It is used to name
+	// the per-tenant Postgres schema created during bootstrap and to name
+	// the bootstrap Job, so it is restricted to a safe identifier charset.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	TenantName string `json:"tenantName"`
+
+	// SchemaBootstrapSQL references a ConfigMap containing the SQL statements
+	// to run against the tenant schema after it is created.
+	// +optional
+	SchemaBootstrapSQL *ConfigMapKeyRef `json:"schemaBootstrapSQL,omitempty"`
+
+	// CredentialSource selects how Postgres connection details are read.
+	// Defaults to the IBM Cloud Operator binding convention, reading
+	// PostgresSecretName.
+	// +optional
+	CredentialSource *CredentialSourceSpec `json:"credentialSource,omitempty"`
+}
+
+// Phase values reported on ECommerceApplicationStatus.Phase.
+const (
+	PhasePending         = "Pending"
+	PhaseBootstrapCreds  = "BootstrapCreds"
+	PhaseBootstrapSchema = "BootstrapSchema"
+	PhaseReady           = "Ready"
+	PhaseFailed          = "Failed"
+)
+
+// ECommerceApplicationStatus defines the observed state of ECommerceApplication
+type ECommerceApplicationStatus struct {
+	// Nodes holds the names of the pods backing the service-catalog Deployment.
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+
+	// BootstrapCompleted reports whether the Postgres schema bootstrap Job for
+	// the current SchemaBootstrapSQL has run to completion.
+	// +optional
+	BootstrapCompleted bool `json:"bootstrapCompleted,omitempty"`
+
+	// Phase summarizes where in the provisioning lifecycle this application
+	// currently is: Pending, BootstrapCreds, BootstrapSchema, Ready or Failed.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// Conditions holds the latest observations of the application's state.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// ECommerceApplication is the Schema for the ecommerceapplications API
+type ECommerceApplication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ECommerceApplicationSpec   `json:"spec,omitempty"`
+	Status ECommerceApplicationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ECommerceApplicationList contains a list of ECommerceApplication
+type ECommerceApplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ECommerceApplication `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ECommerceApplication{}, &ECommerceApplicationList{})
+}
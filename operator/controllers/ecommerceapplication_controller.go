@@ -17,34 +17,61 @@ limitations under the License.
 package controllers
 
 import (
-	b64 "encoding/base64"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 
 	"context"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	cachev1alpha1 "github.com/multi-tenancy/operator/api/v1alpha1"
+	"github.com/multi-tenancy/operator/pkg/credsource"
+	"github.com/multi-tenancy/operator/pkg/job"
+	"github.com/multi-tenancy/operator/pkg/secretmirror"
 
 	batch "k8s.io/api/batch/v1"
 )
 
+// postgresBootstrapImage is the image used by the Job that provisions the
+// per-tenant Postgres schema. It ships psql and nothing else.
+const postgresBootstrapImage = "postgres:14-alpine"
+
+// schemaBootstrapConfigMapKey is the default key inside SchemaBootstrapSQL's
+// ConfigMap that holds the SQL to apply, used when Key is left unset.
+const schemaBootstrapConfigMapKey = "schema.sql"
+
 // ECommerceApplicationReconciler reconciles a Memcached object
 type ECommerceApplicationReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// SyncInterval is how often every ECommerceApplication is re-enqueued to
+	// catch drift in derived resources that wouldn't otherwise trigger a
+	// watch event. Defaults to job.DefaultSyncInterval if zero.
+	// TODO: this repo has no cmd/main.go yet to parse a --sync-interval flag
+	// into it; until one exists, SyncInterval can only be set by whatever
+	// constructs the reconciler directly (e.g. a test), so it's always left
+	// at its zero-value default in practice.
+	SyncInterval time.Duration
 }
 
 //+kubebuilder:rbac:groups=cache.saas.ecommerce.sample.com,resources=ecommerceapplications,verbs=get;list;watch;create;update;patch;delete
@@ -52,6 +79,9 @@ type ECommerceApplicationReconciler struct {
 //+kubebuilder:rbac:groups=cache.saas.ecommerce.sample.com,resources=ecommerceapplications/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -63,63 +93,6 @@ type ECommerceApplicationReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
 
-// Used to deserialize connection strings to IBM Cloud services
-type PostgresBindingJSON struct {
-	Cli      Cli      `json:"cli"`
-	Postgres Postgres `json:"postgres"`
-}
-
-type Cli struct {
-	Arguments   []Argument  `json:"argument"`
-	Bin         string      `json:"bin"`
-	Certificate Certificate `json:"certificate"`
-	Composed    []string    `json:"composed"`
-	Environment Environment `json:"environment"`
-	Type        string      `json:"type"`
-}
-
-type Argument struct {
-	arr []string
-}
-
-type Certificate struct {
-	CertificateAuthority string `json:"certificate_authority"`
-	CertificateBase64    string `json:"certificate_base64"`
-	Name                 string `json:"name"`
-}
-
-type Environment struct {
-	PgpPassword   string `json:"PGPASSWORD"`
-	PgSslRootCert string `json:"PGSSLROOTCERT"`
-}
-
-type Postgres struct {
-	Authentication Authentication `json:"authentication"`
-	Certificate    Certificate    `json:"certificate"`
-	Composed       []string       `json:"composed"`
-	Database       string         `json:"database"`
-	Hosts          []Hosts        `json:"hosts"`
-	Path           string         `json:"path"`
-	QueryOptions   QueryOptions   `json:"query_options"`
-	Scheme         string         `json:"scheme"`
-	Type           string         `json:"type"`
-}
-
-type Authentication struct {
-	Method   string `json:"method"`
-	Password string `json:"password"`
-	Username string `json:"username"`
-}
-
-type Hosts struct {
-	Hostname string `json:"hostname"`
-	Port     int    `json:"port"`
-}
-
-type QueryOptions struct {
-	SslMode string `json:"sslmode"`
-}
-
 func (r *ECommerceApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := ctrllog.FromContext(ctx)
 
@@ -139,143 +112,135 @@ func (r *ECommerceApplicationReconciler) Reconcile(ctx context.Context, req ctrl
 		return ctrl.Result{}, err
 	}
 
-	// Check if the Postgres secret created by IBM Cloud Operator already exists
-	secret := &corev1.Secret{}
-	err = r.Get(ctx, types.NamespacedName{Name: memcached.Spec.PostgresSecretName, Namespace: memcached.Namespace}, secret)
-	if err != nil && errors.IsNotFound(err) {
-		log.Info("Secret does not exist, wait for a while")
-
-		return ctrl.Result{RequeueAfter: time.Second * 300}, nil
-	} else if err == nil {
-
-		//targetSecretName := fmt.Sprintf("%s%s%s", memcached.Spec.PostgresSecretName, "-", memcached.Spec.TenantName)
-		// try to unmarshal the contents of the ICO secret
-		var data PostgresBindingJSON
-		if err := json.Unmarshal(secret.Data["connection"], &data); err != nil {
-			fmt.Println("could not unmarshal:", err)
-			return ctrl.Result{}, err
-		}
-
-		// Create secrets for backend connection to Postgres
-		// Create secret postgres.username
-		targetSecretName := "postgres.username"
-		targetSecret, err := createSecret(targetSecretName, memcached.Namespace, "POSTGRES_USERNAME", data.Postgres.Authentication.Username)
-		// Error creating replicating the secret - requeue the request.
-		if err != nil {
-			return ctrl.Result{}, err
-		}
-
-		err = r.Get(context.TODO(), types.NamespacedName{Name: targetSecret.Name, Namespace: targetSecret.Namespace}, secret)
-		if err != nil && errors.IsNotFound(err) {
-			log.Info(fmt.Sprintf("Target secret %s doesn't exist, creating it", targetSecretName))
-			err = r.Create(context.TODO(), targetSecret)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-		} else {
-			log.Info(fmt.Sprintf("Target secret %s exists, updating it now", targetSecretName))
-			err = r.Update(context.TODO(), targetSecret)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
+	// Fetch and normalize the Postgres connection details, dispatching to
+	// whichever CredentialSource the spec selects (IBM Cloud Operator
+	// binding by default, for backwards compatibility).
+	sourceType := ""
+	sourceRef := memcached.Spec.PostgresSecretName
+	if cs := memcached.Spec.CredentialSource; cs != nil {
+		sourceType = cs.Type
+		if cs.Ref != "" {
+			sourceRef = cs.Ref
 		}
+	}
 
-		// Create secret postgres.password
-		targetSecretName = "postgres.password"
-		targetSecret, err = createSecret(targetSecretName, memcached.Namespace, "POSTGRES_PASSWORD", data.Postgres.Authentication.Password)
-		// Error creating replicating the secret - requeue the request.
-		if err != nil {
-			return ctrl.Result{}, err
-		}
+	source, err := credsource.For(sourceType)
+	if err != nil {
+		log.Error(err, "Unknown credential source type")
+		return ctrl.Result{}, err
+	}
 
-		err = r.Get(context.TODO(), types.NamespacedName{Name: targetSecret.Name, Namespace: targetSecret.Namespace}, secret)
-		if err != nil && errors.IsNotFound(err) {
-			log.Info(fmt.Sprintf("Target secret %s doesn't exist, creating it", targetSecretName))
-			err = r.Create(context.TODO(), targetSecret)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-		} else {
-			log.Info(fmt.Sprintf("Target secret %s exists, updating it now", targetSecretName))
-			err = r.Update(context.TODO(), targetSecret)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-		}
+	creds, err := source.Fetch(ctx, r.Client, memcached.Namespace, sourceRef)
+	if err != nil && errors.IsNotFound(err) {
+		log.Info("Secret does not exist, wait for a while")
 
-		// Create secret postgres.certificate-data
-		targetSecretName = "postgres.certificate-data"
-		decodeArr, _ := b64.StdEncoding.DecodeString(data.Postgres.Certificate.CertificateBase64)
-		certDecoded := string(decodeArr[:])
-		targetSecret, err = createSecret(targetSecretName, memcached.Namespace, "POSTGRES_CERTIFICATE_DATA", certDecoded)
-		// Error creating replicating the secret - requeue the request.
-		if err != nil {
-			return ctrl.Result{}, err
+		if statusErr := r.updateStatus(ctx, memcached, func(s *cachev1alpha1.ECommerceApplicationStatus) {
+			s.Phase = cachev1alpha1.PhasePending
+			meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+				Type:    "Ready",
+				Status:  metav1.ConditionFalse,
+				Reason:  "WaitingForBindingSecret",
+				Message: fmt.Sprintf("waiting for secret %s to be created", sourceRef),
+			})
+		}); statusErr != nil {
+			log.Error(statusErr, "Failed to update ECommerceApplication status")
 		}
 
-		err = r.Get(context.TODO(), types.NamespacedName{Name: targetSecret.Name, Namespace: targetSecret.Namespace}, secret)
-		if err != nil && errors.IsNotFound(err) {
-			log.Info(fmt.Sprintf("Target secret %s doesn't exist, creating it", targetSecretName))
-			err = r.Create(context.TODO(), targetSecret)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-		} else {
-			log.Info(fmt.Sprintf("Target secret %s exists, updating it now", targetSecretName))
-			err = r.Update(context.TODO(), targetSecret)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-		}
+		return ctrl.Result{RequeueAfter: time.Second * 300}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
 
-		// Create secret postgres.url
-		targetSecretName = "postgres.url"
-		postgresUrl := fmt.Sprintf("%s%s%s%d%s%s%s", "jdbc:postgresql://", data.Postgres.Hosts[0].Hostname, ":", data.Postgres.Hosts[0].Port, "/", data.Postgres.Database, "?sslmode=verify-full&sslrootcert=/cloud-postgres-cert")
-		targetSecret, err = createSecret(targetSecretName, memcached.Namespace, "POSTGRES_URL", postgresUrl)
-		// Error creating replicating the secret - requeue the request.
-		if err != nil {
-			return ctrl.Result{}, err
-		}
+	// Derive the postgres.* secrets our workloads consume from the
+	// normalized credentials.
+	credsDoc, err := creds.Document()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	mirror := secretmirror.Mirror{
+		Mappings: []secretmirror.FieldMapping{
+			{JSONPath: "{.username}", TargetSecretName: "postgres.username", TargetSecretKey: "POSTGRES_USERNAME", Transform: secretmirror.TransformIdentity},
+			{JSONPath: "{.password}", TargetSecretName: "postgres.password", TargetSecretKey: "POSTGRES_PASSWORD", Transform: secretmirror.TransformIdentity},
+			{JSONPath: "{.caBundle}", TargetSecretName: "postgres.certificate-data", TargetSecretKey: "POSTGRES_CERTIFICATE_DATA", Transform: secretmirror.TransformIdentity},
+			{JSONPath: "{$}", TargetSecretName: "postgres.url", TargetSecretKey: "POSTGRES_URL", Transform: secretmirror.TransformJDBCURLTemplate},
+		},
+	}
+	if err := mirror.Reconcile(ctx, r.Client, memcached, r.Scheme, credsDoc, memcached.Namespace); err != nil {
+		log.Error(err, "Failed to mirror postgres secrets")
+		return ctrl.Result{}, err
+	}
 
-		err = r.Get(context.TODO(), types.NamespacedName{Name: targetSecret.Name, Namespace: targetSecret.Namespace}, secret)
-		if err != nil && errors.IsNotFound(err) {
-			log.Info(fmt.Sprintf("Target secret %s doesn't exist, creating it", targetSecretName))
-			err = r.Create(context.TODO(), targetSecret)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-		} else {
-			log.Info(fmt.Sprintf("Target secret %s exists, updating it now", targetSecretName))
-			err = r.Update(context.TODO(), targetSecret)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-		}
+	if statusErr := r.updateStatus(ctx, memcached, func(s *cachev1alpha1.ECommerceApplicationStatus) {
+		s.Phase = cachev1alpha1.PhaseBootstrapCreds
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "CredentialsMirrored",
+			Message: "postgres.* secrets mirrored from the credential source",
+		})
+	}); statusErr != nil {
+		log.Error(statusErr, "Failed to update ECommerceApplication status")
+		return ctrl.Result{}, statusErr
+	}
 
-	} else if err != nil {
+	// Create the batch Job that provisions the per-tenant Postgres schema.
+	// The Job name is suffixed with a hash of the bootstrap SQL so that
+	// editing the ConfigMap spawns a new Job instead of silently no-op'ing
+	// against a Job whose spec is immutable.
+	bootstrapSQL, err := r.schemaBootstrapSQL(ctx, memcached)
+	if err != nil {
+		log.Error(err, "Failed to read SchemaBootstrapSQL ConfigMap")
 		return ctrl.Result{}, err
 	}
 
-	// Create batch Job to populate Postgres
-	// How to make sure this only happens once?
-	pgJob, err := createPostgresJob(memcached.Namespace)
-	// Error creating replicating the secret - requeue the request.
+	pgJob, err := r.createPostgresJob(memcached, creds, bootstrapSQL)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	err = r.Get(context.TODO(), types.NamespacedName{Name: pgJob.Name, Namespace: pgJob.Namespace}, pgJob)
-	if err != nil && errors.IsNotFound(err) {
-		log.Info(fmt.Sprintf("Job %s doesn't exist, creating it", pgJob.Name))
-		err = r.Create(context.TODO(), pgJob)
-		if err != nil {
-			return ctrl.Result{}, err
+	existingJob := &batch.Job{}
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		getErr := r.Get(ctx, types.NamespacedName{Name: pgJob.Name, Namespace: pgJob.Namespace}, existingJob)
+		if errors.IsNotFound(getErr) {
+			log.Info(fmt.Sprintf("Job %s doesn't exist, creating it", pgJob.Name))
+			return r.Create(ctx, pgJob)
 		}
-	} else {
-		log.Info(fmt.Sprintf("Job %s exists, updating it now", pgJob.Name))
-		err = r.Update(context.TODO(), pgJob)
-		if err != nil {
-			return ctrl.Result{}, err
+		return getErr
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// BootstrapCompleted always tracks existingJob's own status rather than
+	// sticking at true once set: pgJob.Name is derived from a hash of the
+	// current SchemaBootstrapSQL, so existingJob is always the Job for the
+	// current SQL, and a newer, not-yet-succeeded Job for changed SQL must
+	// be able to flip it back to false instead of leaving a stale true from
+	// a previous generation's Job.
+	completed := existingJob.Status.Succeeded > 0
+	if completed != memcached.Status.BootstrapCompleted {
+		if statusErr := r.updateStatus(ctx, memcached, func(s *cachev1alpha1.ECommerceApplicationStatus) {
+			s.BootstrapCompleted = completed
+			if completed {
+				s.Phase = cachev1alpha1.PhaseReady
+				meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+					Type:    "Ready",
+					Status:  metav1.ConditionTrue,
+					Reason:  "SchemaBootstrapped",
+					Message: fmt.Sprintf("bootstrap job %s completed", pgJob.Name),
+				})
+			} else {
+				s.Phase = cachev1alpha1.PhaseBootstrapSchema
+				meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+					Type:    "Ready",
+					Status:  metav1.ConditionFalse,
+					Reason:  "SchemaBootstrapping",
+					Message: fmt.Sprintf("waiting for bootstrap job %s to complete", pgJob.Name),
+				})
+			}
+		}); statusErr != nil {
+			log.Error(statusErr, "Failed to update ECommerceApplication status")
+			return ctrl.Result{}, statusErr
 		}
 	}
 
@@ -301,8 +266,14 @@ func (r *ECommerceApplicationReconciler) Reconcile(ctx context.Context, req ctrl
 	// Ensure the deployment size is the same as the spec
 	size := memcached.Spec.Size
 	if *found.Spec.Replicas != size {
-		found.Spec.Replicas = &size
-		err = r.Update(ctx, found)
+		err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			latest := &appsv1.Deployment{}
+			if getErr := r.Get(ctx, types.NamespacedName{Name: memcached.Name, Namespace: memcached.Namespace}, latest); getErr != nil {
+				return getErr
+			}
+			latest.Spec.Replicas = &size
+			return r.Update(ctx, latest)
+		})
 		if err != nil {
 			log.Error(err, "Failed to update Deployment", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
 			return ctrl.Result{}, err
@@ -314,27 +285,30 @@ func (r *ECommerceApplicationReconciler) Reconcile(ctx context.Context, req ctrl
 	}
 
 	// Update the Memcached status with the pod names
-	// List the pods for this memcached's deployment
-	//podList := &corev1.PodList{}
-	//listOpts := []client.ListOption{
-	//	client.InNamespace(memcached.Namespace),
-	//	client.MatchingLabels(labelsForMemcached(memcached.Name)),
-	//}
-	//if err = r.List(ctx, podList, listOpts...); err != nil {
-	//	log.Error(err, "Failed to list pods", "Memcached.Namespace", memcached.Namespace, "Memcached.Name", memcached.Name)
-	//	return ctrl.Result{}, err
-	//}
-	//podNames := getPodNames(podList.Items)
-
-	// Update status.Nodes if needed
-	//if !reflect.DeepEqual(podNames, memcached.Status.Nodes) {
-	//	memcached.Status.Nodes = podNames
-	//	err := r.Status().Update(ctx, memcached)
-	//		if err != nil {
-	//		log.Error(err, "Failed to update Memcached status")
-	//		return ctrl.Result{}, err
-	//	}
-	//}
+	podList := &corev1.PodList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(memcached.Namespace),
+		client.MatchingLabels(labelsForMemcached(memcached.Name)),
+	}
+	if err = r.List(ctx, podList, listOpts...); err != nil {
+		log.Error(err, "Failed to list pods", "Memcached.Namespace", memcached.Namespace, "Memcached.Name", memcached.Name)
+		return ctrl.Result{}, err
+	}
+	podNames := getPodNames(podList.Items)
+
+	if statusErr := r.updateStatus(ctx, memcached, func(s *cachev1alpha1.ECommerceApplicationStatus) {
+		s.Nodes = podNames
+		s.Phase = cachev1alpha1.PhaseReady
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionTrue,
+			Reason:  "DeploymentScaled",
+			Message: fmt.Sprintf("deployment running %d/%d replicas", len(podNames), size),
+		})
+	}); statusErr != nil {
+		log.Error(statusErr, "Failed to update ECommerceApplication status")
+		return ctrl.Result{}, statusErr
+	}
 
 	return ctrl.Result{}, nil
 }
@@ -391,63 +365,172 @@ func getPodNames(pods []corev1.Pod) []string {
 	return podNames
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// SetupWithManager sets up the controller with the Manager. It also starts
+// the periodic sync scheduler and wires a watch on the Secrets Mirror
+// derives, so drift in either is caught even without a change to the
+// ECommerceApplication itself.
 func (r *ECommerceApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	scheduler := job.NewScheduler(r.Client, r.SyncInterval)
+	if err := mgr.Add(scheduler); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&cachev1alpha1.ECommerceApplication{}).
 		Owns(&appsv1.Deployment{}).
+		Watches(
+			&source.Channel{Source: scheduler.Events},
+			&handler.EnqueueRequestForObject{},
+		).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			&handler.EnqueueRequestForOwner{OwnerType: &cachev1alpha1.ECommerceApplication{}},
+			builder.WithPredicates(mirroredSecretPredicate()),
+		).
 		Complete(r)
 }
 
-// Create Secret definition
-func createSecret(name string, namespace string, key string, value string) (*corev1.Secret, error) {
-	m := make(map[string]string)
-	//m["POSTGRES_USERNAME"] = data.Postgres.Authentication.Username
-	m[key] = value
-
-	return &corev1.Secret{
-		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
-		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
-		Immutable:  new(bool),
-		Data:       map[string][]byte{},
-		StringData: m,
-		Type:       "Opaque",
-	}, nil
+// mirroredSecretPredicate matches only the Secrets secretmirror.Mirror
+// derives, so the Secret watch doesn't enqueue a reconcile for every Secret
+// in the cluster.
+func mirroredSecretPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetLabels()[secretmirror.OwnedByLabel] == secretmirror.OwnedByECommerceApplication
+	})
+}
+
+// updateStatus re-fetches the ECommerceApplication, applies mutate to its
+// status and persists it via the status subresource, retrying on conflict.
+// memcached is updated in place with the status that was actually written.
+func (r *ECommerceApplicationReconciler) updateStatus(ctx context.Context, memcached *cachev1alpha1.ECommerceApplication, mutate func(*cachev1alpha1.ECommerceApplicationStatus)) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest := &cachev1alpha1.ECommerceApplication{}
+		if err := r.Get(ctx, types.NamespacedName{Name: memcached.Name, Namespace: memcached.Namespace}, latest); err != nil {
+			return err
+		}
+		mutate(&latest.Status)
+		if err := r.Status().Update(ctx, latest); err != nil {
+			return err
+		}
+		latest.Status.DeepCopyInto(&memcached.Status)
+		return nil
+	})
 }
 
-func createPostgresJob(namespace string) (*batch.Job, error) {
-	args := []string{"/bin/sh", "-c", "date; echo Hello from the Kubernetes cluster"}
+// schemaBootstrapSQL fetches the SQL to run against the tenant schema from
+// the ConfigMap referenced by Spec.SchemaBootstrapSQL. It returns an empty
+// string if no ConfigMap is configured.
+func (r *ECommerceApplicationReconciler) schemaBootstrapSQL(ctx context.Context, m *cachev1alpha1.ECommerceApplication) (string, error) {
+	ref := m.Spec.SchemaBootstrapSQL
+	if ref == nil {
+		return "", nil
+	}
+
+	key := ref.Key
+	if key == "" {
+		key = schemaBootstrapConfigMapKey
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: m.Namespace}, cm); err != nil {
+		return "", err
+	}
+
+	return cm.Data[key], nil
+}
+
+// bootstrapSQLHash returns a short hash of the tenant name and bootstrap SQL,
+// used to suffix the Job name so that editing the SQL spawns a new Job
+// instead of being silently ignored by an immutable Job spec.
+func bootstrapSQLHash(tenant, sql string) string {
+	sum := sha256.Sum256([]byte(tenant + "\x00" + sql))
+	return hex.EncodeToString(sum[:])[:8]
+}
 
-	return &batch.Job{
-		TypeMeta: metav1.TypeMeta{Kind: "Job"},
+// bootstrapScript is a fixed shell script that applies the schema-creation
+// and bootstrap SQL passed in via the CREATE_SCHEMA_SQL and BOOTSTRAP_SQL
+// env vars. Keeping the script itself static and passing the SQL through
+// env vars (rather than interpolating it into the script text) means shell
+// quoting never has to round-trip arbitrary SQL: the shell expands
+// "$BOOTSTRAP_SQL" to psql's argv without re-parsing its contents, so
+// newlines, quotes and dollar-quoted ($$ ... $$) bodies all survive intact.
+const bootstrapScript = `set -e
+psql -v ON_ERROR_STOP=1 -c "$CREATE_SCHEMA_SQL"
+psql -v ON_ERROR_STOP=1 -c "$BOOTSTRAP_SQL"
+`
+
+// quoteIdentifier double-quotes a Postgres identifier, escaping any
+// embedded double quotes, so it can safely be spliced into generated SQL
+// even when it contains characters (like the hyphens in a typical
+// Kubernetes-style name) that aren't valid in an unquoted identifier.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// createPostgresJob returns the Job that provisions the tenant's Postgres
+// schema: it creates the schema if needed and then applies bootstrapSQL.
+// The Job is named after a hash of the tenant and SQL so that a change to
+// the bootstrap SQL runs against a fresh Job rather than being swallowed by
+// an already-completed one.
+func (r *ECommerceApplicationReconciler) createPostgresJob(m *cachev1alpha1.ECommerceApplication, conn *credsource.PostgresCreds, bootstrapSQL string) (*batch.Job, error) {
+	tenant := m.Spec.TenantName
+	hash := bootstrapSQLHash(tenant, bootstrapSQL)
+	name := fmt.Sprintf("pg-bootstrap-%s-%s", tenant, hash)
+
+	createSchemaSQL := fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", quoteIdentifier(tenant))
+
+	job := &batch.Job{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:                       "pg",
-			GenerateName:               "",
-			Namespace:                  metav1.NamespaceDefault,
-			SelfLink:                   "",
-			UID:                        "",
-			ResourceVersion:            "",
-			Generation:                 0,
-			CreationTimestamp:          metav1.Time{},
-			DeletionTimestamp:          &metav1.Time{},
-			DeletionGracePeriodSeconds: new(int64),
-			Labels:                     map[string]string{},
-			Annotations:                map[string]string{},
-			OwnerReferences:            []metav1.OwnerReference{},
-			Finalizers:                 []string{},
-			ClusterName:                "",
-			ManagedFields:              []metav1.ManagedFieldsEntry{},
+			Name:      name,
+			Namespace: m.Namespace,
+			Labels:    labelsForMemcached(m.Name),
 		},
 		Spec: batch.JobSpec{
+			BackoffLimit: pointerInt32(3),
 			Template: v1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: namespace},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: m.Namespace},
 				Spec: v1.PodSpec{
+					RestartPolicy: v1.RestartPolicyNever,
 					Containers: []v1.Container{
-						{Image: "bash"},
-						{Args: args},
+						{
+							Name:    "psql",
+							Image:   postgresBootstrapImage,
+							Command: []string{"/bin/sh", "-c", bootstrapScript},
+							Env: []v1.EnvVar{
+								{Name: "PGUSER", ValueFrom: secretKeyRefEnv("postgres.username", "POSTGRES_USERNAME")},
+								{Name: "PGPASSWORD", ValueFrom: secretKeyRefEnv("postgres.password", "POSTGRES_PASSWORD")},
+								{Name: "PGHOST", Value: conn.Host},
+								{Name: "PGPORT", Value: fmt.Sprintf("%d", conn.Port)},
+								{Name: "PGDATABASE", Value: conn.Database},
+								{Name: "PGSSLMODE", Value: conn.SSLMode},
+								{Name: "CREATE_SCHEMA_SQL", Value: createSchemaSQL},
+								{Name: "BOOTSTRAP_SQL", Value: bootstrapSQL},
+							},
+						},
 					},
 				},
 			},
 		},
-	}, nil
+	}
+
+	if err := ctrl.SetControllerReference(m, job, r.Scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// secretKeyRefEnv is a small helper for building EnvVarSource values that
+// pull a single key out of one of the postgres.* secrets.
+func secretKeyRefEnv(secretName, key string) *v1.EnvVarSource {
+	return &v1.EnvVarSource{
+		SecretKeyRef: &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: secretName},
+			Key:                  key,
+		},
+	}
+}
+
+func pointerInt32(v int32) *int32 {
+	return &v
 }
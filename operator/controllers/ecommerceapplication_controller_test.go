@@ -0,0 +1,263 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cachev1alpha1 "github.com/multi-tenancy/operator/api/v1alpha1"
+	"github.com/multi-tenancy/operator/pkg/credsource"
+)
+
+const (
+	testTimeout  = time.Second * 10
+	testInterval = time.Millisecond * 250
+)
+
+// bindingSecret builds the IBM Cloud Operator-shaped binding secret the
+// reconciler reads Postgres connection details out of by default.
+func bindingSecret(name, namespace string) *corev1.Secret {
+	connection, err := json.Marshal(map[string]interface{}{
+		"postgres": map[string]interface{}{
+			"authentication": map[string]string{"username": "tenant-user", "password": "tenant-pass"},
+			"certificate": map[string]string{
+				"certificate_base64": base64.StdEncoding.EncodeToString([]byte("-----BEGIN CERTIFICATE-----\ntest\n-----END CERTIFICATE-----")),
+			},
+			"database":      "ecommerce",
+			"hosts":         []map[string]interface{}{{"hostname": "pg.example.com", "port": 31543}},
+			"query_options": map[string]string{"sslmode": "verify-full"},
+		},
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data:       map[string][]byte{"connection": connection},
+	}
+}
+
+var _ = Describe("ECommerceApplicationReconciler", func() {
+	var namespace string
+
+	BeforeEach(func() {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "ecommerce-test-"}}
+		Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+		namespace = ns.Name
+	})
+
+	It("requeues after 5 minutes when the binding secret is missing", func() {
+		app := &cachev1alpha1.ECommerceApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-secret", Namespace: namespace},
+			Spec: cachev1alpha1.ECommerceApplicationSpec{
+				Size:               1,
+				PostgresSecretName: "does-not-exist",
+				TenantName:         "acme",
+			},
+		}
+		Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+		reconciler := &ECommerceApplicationReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: app.Name, Namespace: namespace}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Minute * 5))
+
+		secret := &corev1.Secret{}
+		Consistently(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: "postgres.username", Namespace: namespace}, secret)
+		}, time.Second).ShouldNot(Succeed())
+	})
+
+	It("materializes the postgres.* secrets from the binding secret", func() {
+		Expect(k8sClient.Create(ctx, bindingSecret("ico-binding", namespace))).To(Succeed())
+
+		app := &cachev1alpha1.ECommerceApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "with-secret", Namespace: namespace},
+			Spec: cachev1alpha1.ECommerceApplicationSpec{
+				Size:               1,
+				PostgresSecretName: "ico-binding",
+				TenantName:         "acme",
+			},
+		}
+		Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+		usernameSecret := &corev1.Secret{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: "postgres.username", Namespace: namespace}, usernameSecret)
+		}, testTimeout, testInterval).Should(Succeed())
+		Expect(usernameSecret.Data["POSTGRES_USERNAME"]).To(Equal([]byte("tenant-user")))
+
+		passwordSecret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "postgres.password", Namespace: namespace}, passwordSecret)).To(Succeed())
+		Expect(passwordSecret.Data["POSTGRES_PASSWORD"]).To(Equal([]byte("tenant-pass")))
+
+		certSecret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "postgres.certificate-data", Namespace: namespace}, certSecret)).To(Succeed())
+		Expect(string(certSecret.Data["POSTGRES_CERTIFICATE_DATA"])).To(ContainSubstring("BEGIN CERTIFICATE"))
+
+		urlSecret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "postgres.url", Namespace: namespace}, urlSecret)).To(Succeed())
+		Expect(string(urlSecret.Data["POSTGRES_URL"])).To(Equal("jdbc:postgresql://pg.example.com:31543/ecommerce?sslmode=verify-full&sslrootcert=/cloud-postgres-cert"))
+	})
+
+	It("materializes the postgres.* secrets from a ZalandoPostgresql credential source, with no sslrootcert", func() {
+		zalando := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "tenant-user.acme-cluster.credentials", Namespace: namespace},
+			Data:       map[string][]byte{"username": []byte("tenant-user"), "password": []byte("tenant-pass")},
+		}
+		Expect(k8sClient.Create(ctx, zalando)).To(Succeed())
+
+		app := &cachev1alpha1.ECommerceApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "zalando-backed", Namespace: namespace},
+			Spec: cachev1alpha1.ECommerceApplicationSpec{
+				Size:               1,
+				PostgresSecretName: zalando.Name,
+				TenantName:         "acme",
+				CredentialSource:   &cachev1alpha1.CredentialSourceSpec{Type: credsource.TypeZalandoPostgresql},
+			},
+		}
+		Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+		usernameSecret := &corev1.Secret{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: "postgres.username", Namespace: namespace}, usernameSecret)
+		}, testTimeout, testInterval).Should(Succeed())
+		Expect(usernameSecret.Data["POSTGRES_USERNAME"]).To(Equal([]byte("tenant-user")))
+
+		urlSecret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "postgres.url", Namespace: namespace}, urlSecret)).To(Succeed())
+		Expect(string(urlSecret.Data["POSTGRES_URL"])).To(Equal("jdbc:postgresql://acme-cluster." + namespace + ".svc.cluster.local:5432/postgres?sslmode=require"))
+	})
+
+	It("materializes the postgres.* secrets from a RawSecret credential source without a CA bundle", func() {
+		raw := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "raw-creds", Namespace: namespace},
+			Data: map[string][]byte{
+				"username": []byte("tenant-user"),
+				"password": []byte("tenant-pass"),
+				"host":     []byte("pg.raw.example.com"),
+				"port":     []byte("5432"),
+				"database": []byte("ecommerce"),
+				"sslmode":  []byte("disable"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, raw)).To(Succeed())
+
+		app := &cachev1alpha1.ECommerceApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "raw-backed", Namespace: namespace},
+			Spec: cachev1alpha1.ECommerceApplicationSpec{
+				Size:               1,
+				PostgresSecretName: raw.Name,
+				TenantName:         "acme",
+				CredentialSource:   &cachev1alpha1.CredentialSourceSpec{Type: credsource.TypeRawSecret},
+			},
+		}
+		Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+		urlSecret := &corev1.Secret{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: "postgres.url", Namespace: namespace}, urlSecret)
+		}, testTimeout, testInterval).Should(Succeed())
+		Expect(string(urlSecret.Data["POSTGRES_URL"])).To(Equal("jdbc:postgresql://pg.raw.example.com:5432/ecommerce?sslmode=disable"))
+	})
+
+	It("creates the Deployment with the requested replicas and rescales it on Spec.Size changes", func() {
+		Expect(k8sClient.Create(ctx, bindingSecret("ico-binding-deploy", namespace))).To(Succeed())
+
+		app := &cachev1alpha1.ECommerceApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "scaling", Namespace: namespace},
+			Spec: cachev1alpha1.ECommerceApplicationSpec{
+				Size:               2,
+				PostgresSecretName: "ico-binding-deploy",
+				TenantName:         "acme",
+			},
+		}
+		Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+		dep := &appsv1.Deployment{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: app.Name, Namespace: namespace}, dep)
+		}, testTimeout, testInterval).Should(Succeed())
+		Expect(*dep.Spec.Replicas).To(Equal(int32(2)))
+
+		Eventually(func() error {
+			latest := &cachev1alpha1.ECommerceApplication{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: app.Name, Namespace: namespace}, latest); err != nil {
+				return err
+			}
+			latest.Spec.Size = 4
+			return k8sClient.Update(ctx, latest)
+		}, testTimeout, testInterval).Should(Succeed())
+
+		Eventually(func() (int32, error) {
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: app.Name, Namespace: namespace}, dep); err != nil {
+				return 0, err
+			}
+			return *dep.Spec.Replicas, nil
+		}, testTimeout, testInterval).Should(Equal(int32(4)))
+	})
+
+	It("creates the bootstrap Job exactly once per SQL hash", func() {
+		Expect(k8sClient.Create(ctx, bindingSecret("ico-binding-job", namespace))).To(Succeed())
+
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "schema-sql", Namespace: namespace},
+			Data:       map[string]string{schemaBootstrapConfigMapKey: "CREATE TABLE orders (id serial);"},
+		}
+		Expect(k8sClient.Create(ctx, cm)).To(Succeed())
+
+		app := &cachev1alpha1.ECommerceApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-job", Namespace: namespace},
+			Spec: cachev1alpha1.ECommerceApplicationSpec{
+				Size:               1,
+				PostgresSecretName: "ico-binding-job",
+				TenantName:         "acme",
+				SchemaBootstrapSQL: &cachev1alpha1.ConfigMapKeyRef{Name: "schema-sql"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+		expectedName := fmt.Sprintf("pg-bootstrap-acme-%s", bootstrapSQLHash("acme", "CREATE TABLE orders (id serial);"))
+		job := &batch.Job{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: expectedName, Namespace: namespace}, job)
+		}, testTimeout, testInterval).Should(Succeed())
+
+		// A second reconcile (triggered by the Deployment being created just
+		// after) must not spawn another Job for the same tenant and SQL.
+		jobList := &batch.JobList{}
+		Consistently(func() (int, error) {
+			if err := k8sClient.List(ctx, jobList, client.InNamespace(namespace)); err != nil {
+				return 0, err
+			}
+			return len(jobList.Items), nil
+		}, time.Second).Should(Equal(1))
+	})
+})
@@ -0,0 +1,95 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credsource normalizes Postgres connection details read from the
+// Secret conventions used by different cluster-provisioning backends (the
+// IBM Cloud Operator binding, the Zalando postgres-operator, or a plain
+// user-supplied Secret) into a single PostgresCreds shape.
+package credsource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PostgresCreds is the normalized set of Postgres connection details every
+// CredentialSource implementation produces, regardless of how the backing
+// service stores them.
+type PostgresCreds struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Database string `json:"database"`
+	// CABundle is the PEM-encoded CA certificate to verify the Postgres
+	// server against, decoded if the source stores it encoded. May be empty
+	// for backends (e.g. ZalandoPostgresql) or RawSecrets that don't supply
+	// one. Deliberately not omitempty: Document()'s output is consumed by a
+	// fixed set of secretmirror.FieldMapping JSONPaths that expect the key
+	// to always be present, even if its value is "".
+	CABundle string `json:"caBundle"`
+	SSLMode  string `json:"sslMode"`
+}
+
+// Document returns creds as a generic JSON document, for driving a
+// secretmirror.Mirror.
+func (c *PostgresCreds) Document() (map[string]interface{}, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// CredentialSource reads and normalizes Postgres connection details out of
+// a Secret following a specific provisioning backend's conventions.
+type CredentialSource interface {
+	// Fetch reads the Secret named ref in namespace and normalizes its
+	// contents into PostgresCreds. It returns the apierrors NotFound error
+	// unwrapped if ref does not exist, so callers can treat it the same as
+	// a direct client.Get.
+	Fetch(ctx context.Context, c client.Client, namespace, ref string) (*PostgresCreds, error)
+}
+
+// Supported values for ECommerceApplicationSpec.CredentialSource.Type.
+const (
+	TypeIBMCloudBinding   = "IBMCloudBinding"
+	TypeZalandoPostgresql = "ZalandoPostgresql"
+	TypeRawSecret         = "RawSecret"
+)
+
+// For returns the CredentialSource implementation for sourceType. An empty
+// sourceType defaults to TypeIBMCloudBinding, so CRs created before
+// CredentialSource existed keep working unchanged.
+func For(sourceType string) (CredentialSource, error) {
+	switch sourceType {
+	case TypeIBMCloudBinding, "":
+		return ibmCloudBinding{}, nil
+	case TypeZalandoPostgresql:
+		return zalandoPostgresql{}, nil
+	case TypeRawSecret:
+		return rawSecret{}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential source type %q", sourceType)
+	}
+}
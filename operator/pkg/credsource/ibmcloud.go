@@ -0,0 +1,95 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credsource
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// postgresBindingJSON is the shape of the JSON the IBM Cloud Operator
+// writes under its binding Secret's "connection" key.
+type postgresBindingJSON struct {
+	Postgres postgresBinding `json:"postgres"`
+}
+
+type postgresBinding struct {
+	Authentication postgresAuthentication `json:"authentication"`
+	Certificate    postgresCertificate    `json:"certificate"`
+	Database       string                 `json:"database"`
+	Hosts          []postgresHost         `json:"hosts"`
+	QueryOptions   postgresQueryOptions   `json:"query_options"`
+}
+
+type postgresAuthentication struct {
+	Password string `json:"password"`
+	Username string `json:"username"`
+}
+
+type postgresCertificate struct {
+	CertificateBase64 string `json:"certificate_base64"`
+}
+
+type postgresHost struct {
+	Hostname string `json:"hostname"`
+	Port     int    `json:"port"`
+}
+
+type postgresQueryOptions struct {
+	SslMode string `json:"sslmode"`
+}
+
+// ibmCloudBinding reads Postgres credentials out of the connection JSON an
+// IBM Cloud Operator-managed binding Secret stores under its "connection"
+// key.
+type ibmCloudBinding struct{}
+
+func (ibmCloudBinding) Fetch(ctx context.Context, c client.Client, namespace, ref string) (*PostgresCreds, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref, Namespace: namespace}, secret); err != nil {
+		return nil, err
+	}
+
+	var doc postgresBindingJSON
+	if err := json.Unmarshal(secret.Data["connection"], &doc); err != nil {
+		return nil, fmt.Errorf("unmarshalling binding secret %s: %w", ref, err)
+	}
+	if len(doc.Postgres.Hosts) == 0 {
+		return nil, fmt.Errorf("binding secret %s has no hosts", ref)
+	}
+
+	caBundle, err := base64.StdEncoding.DecodeString(doc.Postgres.Certificate.CertificateBase64)
+	if err != nil {
+		return nil, fmt.Errorf("decoding certificate in binding secret %s: %w", ref, err)
+	}
+
+	return &PostgresCreds{
+		Username: doc.Postgres.Authentication.Username,
+		Password: doc.Postgres.Authentication.Password,
+		Host:     doc.Postgres.Hosts[0].Hostname,
+		Port:     doc.Postgres.Hosts[0].Port,
+		Database: doc.Postgres.Database,
+		CABundle: string(caBundle),
+		SSLMode:  doc.Postgres.QueryOptions.SslMode,
+	}, nil
+}
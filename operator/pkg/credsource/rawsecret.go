@@ -0,0 +1,54 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credsource
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rawSecret reads Postgres credentials directly out of a user-supplied
+// Secret whose keys already match PostgresCreds' fields: username,
+// password, host, port, database, and the optional ca.crt and sslmode.
+type rawSecret struct{}
+
+func (rawSecret) Fetch(ctx context.Context, c client.Client, namespace, ref string) (*PostgresCreds, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref, Namespace: namespace}, secret); err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.Atoi(string(secret.Data["port"]))
+	if err != nil {
+		return nil, fmt.Errorf("secret %s: invalid port %q: %w", ref, secret.Data["port"], err)
+	}
+
+	return &PostgresCreds{
+		Username: string(secret.Data["username"]),
+		Password: string(secret.Data["password"]),
+		Host:     string(secret.Data["host"]),
+		Port:     port,
+		Database: string(secret.Data["database"]),
+		CABundle: string(secret.Data["ca.crt"]),
+		SSLMode:  string(secret.Data["sslmode"]),
+	}, nil
+}
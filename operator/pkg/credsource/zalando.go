@@ -0,0 +1,60 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credsource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// zalandoCredentialsSuffix is the suffix the Zalando postgres-operator
+// appends to the per-role Secrets it generates.
+const zalandoCredentialsSuffix = ".credentials"
+
+// zalandoPostgresql reads credentials out of the per-role Secret the
+// Zalando postgres-operator generates, named "<user>.<cluster>.credentials",
+// and derives the in-cluster service host from the cluster name encoded in
+// that name.
+type zalandoPostgresql struct{}
+
+func (zalandoPostgresql) Fetch(ctx context.Context, c client.Client, namespace, ref string) (*PostgresCreds, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref, Namespace: namespace}, secret); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSuffix(ref, zalandoCredentialsSuffix)
+	parts := strings.SplitN(name, ".", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("secret name %q does not match the <user>.<cluster>%s pattern", ref, zalandoCredentialsSuffix)
+	}
+	cluster := parts[1]
+
+	return &PostgresCreds{
+		Username: string(secret.Data["username"]),
+		Password: string(secret.Data["password"]),
+		Host:     fmt.Sprintf("%s.%s.svc.cluster.local", cluster, namespace),
+		Port:     5432,
+		Database: "postgres",
+		SSLMode:  "require",
+	}, nil
+}
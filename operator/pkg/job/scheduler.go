@@ -0,0 +1,98 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package job periodically re-reconciles every ECommerceApplication,
+// complementing the event-driven reconciliation triggered by watches: a
+// change no watch covers (e.g. someone rotates the binding secret
+// out-of-band) is only otherwise detected when the ECommerceApplication
+// itself changes.
+package job
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	cachev1alpha1 "github.com/multi-tenancy/operator/api/v1alpha1"
+)
+
+// DefaultSyncInterval is the interval NewScheduler uses when it is given a
+// zero or negative interval. No command-line flag sets the interval.
+const DefaultSyncInterval = 5 * time.Minute
+
+// Scheduler is a manager.Runnable that, every Interval, lists all
+// ECommerceApplications and emits a GenericEvent for each on Events. A
+// controller watching Events through a source.Channel is re-enqueued for
+// every one of them, even though none of its watched objects actually
+// changed.
+type Scheduler struct {
+	Client   client.Client
+	Interval time.Duration
+	Events   chan event.GenericEvent
+}
+
+// NewScheduler returns a Scheduler that syncs every interval, or
+// DefaultSyncInterval if interval is zero.
+func NewScheduler(c client.Client, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+	return &Scheduler{
+		Client:   c,
+		Interval: interval,
+		Events:   make(chan event.GenericEvent),
+	}
+}
+
+// Start implements manager.Runnable. It blocks until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) error {
+	log := ctrllog.FromContext(ctx).WithName("sync-scheduler")
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.sync(ctx); err != nil {
+				log.Error(err, "Failed to list ECommerceApplications for periodic sync")
+			}
+		}
+	}
+}
+
+// sync lists every ECommerceApplication and enqueues it on s.Events,
+// returning early if ctx is cancelled while it is still sending.
+func (s *Scheduler) sync(ctx context.Context) error {
+	var apps cachev1alpha1.ECommerceApplicationList
+	if err := s.Client.List(ctx, &apps); err != nil {
+		return err
+	}
+
+	for i := range apps.Items {
+		select {
+		case s.Events <- event.GenericEvent{Object: &apps.Items[i]}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secretmirror derives one or more target Secrets from an already
+// decoded JSON-like document according to a declarative list of field
+// mappings, instead of each derived Secret needing hand-written Go code.
+package secretmirror
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Transform converts the raw value a FieldMapping's JSONPath selects into
+// the string stored in the target Secret.
+type Transform string
+
+const (
+	// TransformIdentity stores the selected value as-is (formatted as text).
+	TransformIdentity Transform = "identity"
+	// TransformBase64Decode base64-decodes the selected string value.
+	TransformBase64Decode Transform = "base64-decode"
+	// TransformJDBCURLTemplate assembles a JDBC connection string out of the
+	// "host", "port" and "database" fields of the object the JSONPath
+	// selects.
+	TransformJDBCURLTemplate Transform = "jdbc-url-template"
+)
+
+// FieldMapping describes how to derive one key of one target Secret from a
+// decoded document.
+type FieldMapping struct {
+	// JSONPath selects a value out of the document, using kubectl-style
+	// jsonpath syntax (e.g. "{.authentication.username}", or "{$}" to
+	// select the whole document).
+	JSONPath string
+	// TargetSecretName is the Secret the selected value is written to.
+	// Several mappings may share a TargetSecretName to populate multiple
+	// keys of the same Secret.
+	TargetSecretName string
+	// TargetSecretKey is the key within TargetSecretName the value is
+	// written under.
+	TargetSecretKey string
+	// Transform is applied to the selected value before it is stored.
+	// Defaults to TransformIdentity.
+	Transform Transform
+}
+
+const (
+	// OwnedByLabel is set on every Secret Mirror creates, so a controller can
+	// cheaply watch just the Secrets it derives instead of every Secret in
+	// the cluster.
+	OwnedByLabel = "owned-by"
+	// OwnedByECommerceApplication is OwnedByLabel's value on Secrets derived
+	// for an ECommerceApplication.
+	OwnedByECommerceApplication = "ecommerceapplication"
+)
+
+// Mirror derives a set of target Secrets from a decoded document according
+// to Mappings.
+type Mirror struct {
+	// Mappings lists the fields to derive from the document passed to
+	// Reconcile.
+	Mappings []FieldMapping
+}
+
+// Reconcile evaluates every mapping against doc and creates or updates the
+// resulting target Secrets in namespace. Each target Secret is given owner
+// as an OwnerReference so it is garbage collected along with owner.
+func (m *Mirror) Reconcile(ctx context.Context, c client.Client, owner client.Object, scheme *runtime.Scheme, doc interface{}, namespace string) error {
+	targets := map[string]map[string]string{}
+	for _, fm := range m.Mappings {
+		value, err := fm.extract(doc)
+		if err != nil {
+			return fmt.Errorf("mapping %s -> %s/%s: %w", fm.JSONPath, fm.TargetSecretName, fm.TargetSecretKey, err)
+		}
+		if targets[fm.TargetSecretName] == nil {
+			targets[fm.TargetSecretName] = map[string]string{}
+		}
+		targets[fm.TargetSecretName][fm.TargetSecretKey] = value
+	}
+
+	for name, data := range targets {
+		target := &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{OwnedByLabel: OwnedByECommerceApplication},
+			},
+			StringData: data,
+			Type:       corev1.SecretTypeOpaque,
+		}
+		if err := controllerutil.SetControllerReference(owner, target, scheme); err != nil {
+			return fmt.Errorf("setting owner reference on secret %s: %w", name, err)
+		}
+		if err := createOrUpdate(ctx, c, target); err != nil {
+			return fmt.Errorf("creating or updating secret %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// createOrUpdate creates target, or updates it in place if it already
+// exists, retrying on conflict.
+func createOrUpdate(ctx context.Context, c client.Client, target *corev1.Secret) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		existing := &corev1.Secret{}
+		err := c.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: target.Namespace}, existing)
+		if errors.IsNotFound(err) {
+			return c.Create(ctx, target)
+		}
+		if err != nil {
+			return err
+		}
+		target.ResourceVersion = existing.ResourceVersion
+		return c.Update(ctx, target)
+	})
+}
+
+// extract selects fm.JSONPath out of doc and applies fm.Transform.
+func (fm FieldMapping) extract(doc interface{}) (string, error) {
+	jp := jsonpath.New(fm.TargetSecretName + "/" + fm.TargetSecretKey)
+	if err := jp.Parse(fm.JSONPath); err != nil {
+		return "", fmt.Errorf("invalid JSONPath %q: %w", fm.JSONPath, err)
+	}
+
+	results, err := jp.FindResults(doc)
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 || len(results[0]) == 0 {
+		return "", fmt.Errorf("JSONPath %q matched nothing", fm.JSONPath)
+	}
+	raw := results[0][0].Interface()
+
+	switch fm.Transform {
+	case TransformBase64Decode:
+		s, ok := raw.(string)
+		if !ok {
+			return "", fmt.Errorf("base64-decode transform requires a string, got %T", raw)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return "", err
+		}
+		return string(decoded), nil
+	case TransformJDBCURLTemplate:
+		return jdbcURL(raw)
+	case TransformIdentity, "":
+		return fmt.Sprintf("%v", raw), nil
+	default:
+		return "", fmt.Errorf("unknown transform %q", fm.Transform)
+	}
+}
+
+// jdbcURL assembles a JDBC connection string out of the "host", "port",
+// "database" and "sslMode" fields of the object raw. The "sslrootcert"
+// query parameter, which points at where the IBM Cloud Operator convention
+// mounts its CA bundle, is only added when raw carries a non-empty
+// "caBundle" - credential sources that don't supply one (e.g.
+// ZalandoPostgresql, or a RawSecret without ca.crt) would otherwise produce
+// a URL referencing a cert file that's never mounted.
+func jdbcURL(raw interface{}) (string, error) {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return "", err
+	}
+
+	var conn struct {
+		Host     string `json:"host"`
+		Port     int    `json:"port"`
+		Database string `json:"database"`
+		SSLMode  string `json:"sslMode"`
+		CABundle string `json:"caBundle"`
+	}
+	if err := json.Unmarshal(b, &conn); err != nil {
+		return "", err
+	}
+	if conn.Host == "" {
+		return "", fmt.Errorf("jdbc-url-template: no host in %s", b)
+	}
+	if conn.SSLMode == "" {
+		conn.SSLMode = "verify-full"
+	}
+
+	url := fmt.Sprintf("jdbc:postgresql://%s:%d/%s?sslmode=%s", conn.Host, conn.Port, conn.Database, conn.SSLMode)
+	if conn.CABundle != "" {
+		url += "&sslrootcert=/cloud-postgres-cert"
+	}
+	return url, nil
+}